@@ -1,253 +1,464 @@
 package circuit_breaker
 
 import (
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/alibaba/sentinel-golang/core/base"
-	"github.com/alibaba/sentinel-golang/core/stat"
 	"github.com/alibaba/sentinel-golang/util"
 )
 
 type CircuitBreaker interface {
-	getRule() Rule
+	// BoundRule returns the associated circuit breaking rule.
+	BoundRule() Rule
+	// CurrentState returns the current State of the circuit breaker.
+	CurrentState() State
 	TryPass(ctx *base.EntryContext) bool
+	// OnRequestComplete is called after the guarded call finishes, so that the breaker can observe the
+	// outcome of probe requests admitted while in the HalfOpen state and decide the next transition.
+	OnRequestComplete(rt int64, err error)
+	// OnCompleted feeds a single Pass/Block/Complete/Error/Rt event into the breaker's own sliding
+	// window. It is called by StatSlot for every breaker guarding the entered resource, so that the
+	// breaker's statistics no longer depend on the shared ResourceNode.
+	OnCompleted(event base.MetricEvent, rt int64)
+}
+
+// circuitBreakerBase encapsulates the state machine (Closed/Open/HalfOpen) and the transitions among
+// them that are shared by every circuit breaker strategy, so that each concrete breaker only needs to
+// implement its own trip condition and probe judgement. It also owns the breaker's dedicated stat
+// window, so two rules on the same resource with different windows never collide, and business
+// events counted by other slots (flow, system, ...) never pollute the breaker's view.
+type circuitBreakerBase struct {
+	// state is the current State of the breaker, managed atomically.
+	state int32
+	// openedAt is the unix nanosecond timestamp at which the breaker last entered the Open state,
+	// managed atomically. It is only meaningful while state == Open or HalfOpen.
+	openedAt int64
+	// halfOpenMux guards the Open->HalfOpen transition together with the reset and admission of
+	// halfOpenProbes, so that a probe admitted by one goroutine can never be wiped out by another
+	// goroutine concurrently winning the Open->HalfOpen transition.
+	halfOpenMux sync.Mutex
+	// halfOpenProbes counts the probe requests admitted during the current HalfOpen window,
+	// guarded by halfOpenMux. It is reset whenever the breaker (re-)enters HalfOpen.
+	halfOpenProbes int64
+	// stat is the breaker's own sliding-window statistic, populated by OnCompleted.
+	stat *circuitBreakerStat
+	// forcedRecoverTimeoutMs overrides the rule's RecoverTimeout while positive; it backs ForceOpen.
+	forcedRecoverTimeoutMs int64
+}
+
+func (b *circuitBreakerBase) CurrentState() State {
+	return State(atomic.LoadInt32(&b.state))
+}
+
+// forceOpen unconditionally puts the breaker into the Open state and keeps it there for at least
+// d, regardless of the strategy's own trip condition. It backs the package-level ForceOpen function.
+// rule is forwarded to state-change listeners, exactly like a natural trip does.
+func (b *circuitBreakerBase) forceOpen(rule Rule, d time.Duration) {
+	prev := b.CurrentState()
+	atomic.StoreInt64(&b.forcedRecoverTimeoutMs, d.Milliseconds())
+	atomic.StoreInt32(&b.state, int32(Open))
+	atomic.StoreInt64(&b.openedAt, time.Now().UnixNano())
+	onTransformToOpen(prev, rule, nil)
+}
+
+// forceReset clears any ForceOpen override and returns the breaker to Closed, as if freshly
+// built. It backs the package-level Reset function. rule is forwarded to state-change listeners,
+// exactly like a natural recovery does. It also clears the breaker's stat window, so stale samples
+// from before the reset can never make it retrip immediately; concrete breakers that keep their own
+// counters alongside circuitBreakerBase (e.g. averageRtCircuitBreaker.passCount) override forceReset
+// to clear those too.
+func (b *circuitBreakerBase) forceReset(rule Rule) {
+	prev := b.CurrentState()
+	atomic.StoreInt64(&b.forcedRecoverTimeoutMs, 0)
+	b.halfOpenMux.Lock()
+	b.halfOpenProbes = 0
+	b.halfOpenMux.Unlock()
+	b.stat.reset()
+	atomic.StoreInt32(&b.state, int32(Closed))
+	onTransformToClosed(prev, rule)
+}
+
+// OnCompleted feeds the event into the breaker's dedicated stat window.
+func (b *circuitBreakerBase) OnCompleted(event base.MetricEvent, rt int64) {
+	b.stat.record(event, rt)
+}
+
+// fromClosedToOpen transitions the breaker from Closed to Open and records openedAt.
+// It returns true if the caller performed the transition. snapshot is the statistic value
+// (e.g. average RT, error ratio) that triggered the trip, and is forwarded to state-change listeners.
+func (b *circuitBreakerBase) fromClosedToOpen(rule Rule, snapshot interface{}) bool {
+	if atomic.CompareAndSwapInt32(&b.state, int32(Closed), int32(Open)) {
+		// A natural trip always follows the rule's own RecoverTimeout; clear any stale
+		// ForceOpen override left over from an earlier forced maintenance window.
+		atomic.StoreInt64(&b.forcedRecoverTimeoutMs, 0)
+		atomic.StoreInt64(&b.openedAt, time.Now().UnixNano())
+		onTransformToOpen(Closed, rule, snapshot)
+		return true
+	}
+	return false
+}
+
+// retryTimeoutArrived reports whether RecoverTimeout has elapsed since the breaker opened. A
+// positive forcedRecoverTimeoutMs (set by ForceOpen) overrides the rule's RecoverTimeout.
+func (b *circuitBreakerBase) retryTimeoutArrived(recoverTimeout uint32) bool {
+	timeout := time.Duration(recoverTimeout) * time.Second
+	if forced := atomic.LoadInt64(&b.forcedRecoverTimeoutMs); forced > 0 {
+		timeout = time.Duration(forced) * time.Millisecond
+	}
+	openedAt := atomic.LoadInt64(&b.openedAt)
+	return time.Since(time.Unix(0, openedAt)) >= timeout
+}
+
+// fromOpenToHalfOpen transitions the breaker from Open to HalfOpen and resets the probe counter.
+// It returns true if the caller performed the transition. The reset is serialized against
+// tryAdmitProbe via halfOpenMux, so a probe admitted right as another goroutine wins this
+// transition can never be silently reset away. Listeners are notified after halfOpenMux is
+// released, since they run arbitrary user code that must not run while holding it (a listener
+// that calls back into TryPass on the same breaker would otherwise deadlock on tryAdmitProbe).
+func (b *circuitBreakerBase) fromOpenToHalfOpen(rule Rule) bool {
+	b.halfOpenMux.Lock()
+	transformed := atomic.CompareAndSwapInt32(&b.state, int32(Open), int32(HalfOpen))
+	if transformed {
+		b.halfOpenProbes = 0
+	}
+	b.halfOpenMux.Unlock()
+	if transformed {
+		onTransformToHalfOpen(Open, rule)
+	}
+	return transformed
+}
+
+// tryAdmitProbe admits at most maxCalls probe requests while the breaker is HalfOpen.
+func (b *circuitBreakerBase) tryAdmitProbe(maxCalls uint32) bool {
+	b.halfOpenMux.Lock()
+	defer b.halfOpenMux.Unlock()
+	if b.CurrentState() != HalfOpen {
+		return false
+	}
+	b.halfOpenProbes++
+	return b.halfOpenProbes <= int64(maxCalls)
+}
+
+// fromHalfOpenToClosed transitions the breaker from HalfOpen to Closed, meaning the probe succeeded.
+func (b *circuitBreakerBase) fromHalfOpenToClosed(rule Rule) bool {
+	if atomic.CompareAndSwapInt32(&b.state, int32(HalfOpen), int32(Closed)) {
+		atomic.StoreInt64(&b.forcedRecoverTimeoutMs, 0)
+		onTransformToClosed(HalfOpen, rule)
+		return true
+	}
+	return false
+}
+
+// fromHalfOpenToOpen transitions the breaker from HalfOpen back to Open, meaning the probe failed.
+// openedAt is refreshed so that the next retry window starts counting from now.
+func (b *circuitBreakerBase) fromHalfOpenToOpen(rule Rule, snapshot interface{}) bool {
+	if atomic.CompareAndSwapInt32(&b.state, int32(HalfOpen), int32(Open)) {
+		atomic.StoreInt64(&b.openedAt, time.Now().UnixNano())
+		onTransformToOpen(HalfOpen, rule, snapshot)
+		return true
+	}
+	return false
 }
 
 // average rt circuit breaker will cut resource if the rt of resource exceed the threshold of rule.
 type averageRtCircuitBreaker struct {
-	// status of the circuit breaker
-	cut util.AtomicBool
+	circuitBreakerBase
 	// the count of request exceed the threshold
 	passCount int64
 	rule      *averageRtRule
-	metric    base.ReadStat
 }
 
 func newAverageRtCircuitBreaker(rule *averageRtRule) *averageRtCircuitBreaker {
-	resNode := stat.GetResourceNode(rule.Resource)
-	var metric base.ReadStat
-	// TODO need to optimize, we should to handle the scenario that resNode is nil
-	if resNode != nil {
-		metric = resNode.GetOrCreateSlidingWindowMetric(rule.SampleCount, rule.IntervalInMs)
-	}
-	return &averageRtCircuitBreaker{
-		rule:   rule,
-		metric: metric,
-	}
+	return newAverageRtCircuitBreakerWithStat(rule, newCircuitBreakerStat(rule.SampleCount, rule.IntervalInMs))
 }
 
 // For test
-func newAverageRtCircuitBreakerWithMetric(rule *averageRtRule, metric base.ReadStat) *averageRtCircuitBreaker {
-	return &averageRtCircuitBreaker{
-		rule:   rule,
-		metric: metric,
-	}
+func newAverageRtCircuitBreakerWithStat(rule *averageRtRule, stat *circuitBreakerStat) *averageRtCircuitBreaker {
+	b := &averageRtCircuitBreaker{rule: rule}
+	b.stat = stat
+	return b
 }
 
-func (b averageRtCircuitBreaker) getRule() Rule {
+func (b *averageRtCircuitBreaker) BoundRule() Rule {
 	return b.rule
 }
 
 func (b *averageRtCircuitBreaker) TryPass(_ *base.EntryContext) bool {
-	// currently, the breaker is before auto recover, direct return blocked .
-	if b.cut.Get() {
-		return false
-	}
 	rule := b.rule
 	if rule == nil {
 		return true
 	}
-
-	// TODO need to optimize here.
-	// We might create individual stat structures for circuit breakers, rather than use the universal ResourceNode.
-	if b.metric == nil {
-		resNode := stat.GetResourceNode(rule.Resource)
-		if resNode == nil {
-			logger.Errorf("Resource(%s)'s stat node is nil.", rule.Resource)
-			return true
+	switch b.CurrentState() {
+	case Open:
+		if !b.retryTimeoutArrived() {
+			return false
 		}
-		b.metric = resNode.GetOrCreateSlidingWindowMetric(rule.SampleCount, rule.IntervalInMs)
-		logger.Errorf("Delayed to initialize the metric of averageRtCircuitBreaker.")
+		b.fromOpenToHalfOpen(rule)
+		return b.tryAdmitProbe(rule.halfOpenMaxCalls())
+	case HalfOpen:
+		return b.tryAdmitProbe(rule.halfOpenMaxCalls())
+	default:
+		return b.tryPassClosed(rule)
 	}
+}
 
-	avgRt := b.metric.AvgRT()
-	if avgRt < rule.Threshold {
+func (b *averageRtCircuitBreaker) tryPassClosed(rule *averageRtRule) bool {
+	avgRt := b.stat.avgRT()
+	if avgRt < float64(rule.Threshold) {
 		atomic.StoreInt64(&b.passCount, 0)
 		return true
 	}
-	if util.IncrementAndGetInt64(&b.passCount) < rule.RtSlowRequestAmount {
+	if util.IncrementAndGetInt64(&b.passCount) < int64(rule.RtSlowRequestAmount) {
 		return true
 	}
-	// trigger circuit breaker
-	if b.cut.CompareAndSet(false, true) {
-		go util.RunWithRecover(func() {
-			// recover after RecoverTimeout seconds
-			time.Sleep(time.Second * time.Duration(rule.RecoverTimeout))
+	// trigger circuit breaker: the request that triggers the trip is rejected as well.
+	b.fromClosedToOpen(rule, avgRt)
+	return false
+}
+
+func (b *averageRtCircuitBreaker) OnRequestComplete(rt int64, err error) {
+	if b.CurrentState() != HalfOpen {
+		return
+	}
+	if err == nil && rt < int64(b.rule.Threshold) {
+		if b.fromHalfOpenToClosed(b.rule) {
 			atomic.StoreInt64(&b.passCount, 0)
-			b.cut.Set(false)
-		}, logger)
+		}
+	} else {
+		b.fromHalfOpenToOpen(b.rule, rt)
 	}
-	return false
+}
+
+func (b *averageRtCircuitBreaker) retryTimeoutArrived() bool {
+	return b.circuitBreakerBase.retryTimeoutArrived(b.rule.RecoverTimeout)
+}
+
+// forceReset also clears passCount, since circuitBreakerBase.forceReset only knows about the stat
+// window it owns directly.
+func (b *averageRtCircuitBreaker) forceReset(rule Rule) {
+	atomic.StoreInt64(&b.passCount, 0)
+	b.circuitBreakerBase.forceReset(rule)
 }
 
 // error ratio circuit breaker will cut resource if the error ratio of resource exceed the threshold of rule.
 type errorRatioCircuitBreaker struct {
-	// status of the breaker
-	cut util.AtomicBool
-	// the count of request exceed the threshold
-	passCount int64
-	rule      *errorRatioRule
-	metric    base.ReadStat
+	circuitBreakerBase
+	rule *errorRatioRule
 }
 
 func newErrorRatioCircuitBreaker(rule *errorRatioRule) *errorRatioCircuitBreaker {
-	resNode := stat.GetResourceNode(rule.Resource)
-	var metric base.ReadStat
-	// TODO need to optimize, we should to handle the scenario that resNode is nil
-	if resNode != nil {
-		metric = resNode.GetOrCreateSlidingWindowMetric(rule.SampleCount, rule.IntervalInMs)
-	}
-	return &errorRatioCircuitBreaker{
-		rule:   rule,
-		metric: metric,
-	}
+	return newErrorRatioCircuitBreakerWithStat(rule, newCircuitBreakerStat(rule.SampleCount, rule.IntervalInMs))
 }
 
-func newErrorRatioCircuitBreakerWithMetric(rule *errorRatioRule, metric base.ReadStat) *errorRatioCircuitBreaker {
-	return &errorRatioCircuitBreaker{
-		rule:   rule,
-		metric: metric,
-	}
+func newErrorRatioCircuitBreakerWithStat(rule *errorRatioRule, stat *circuitBreakerStat) *errorRatioCircuitBreaker {
+	b := &errorRatioCircuitBreaker{rule: rule}
+	b.stat = stat
+	return b
 }
 
-func (b *errorRatioCircuitBreaker) getRule() Rule {
+func (b *errorRatioCircuitBreaker) BoundRule() Rule {
 	return b.rule
 }
 
 func (b *errorRatioCircuitBreaker) TryPass(_ *base.EntryContext) bool {
-	if b.cut.Get() {
-		return false
-	}
-
 	rule := b.rule
 	if rule == nil {
 		return true
 	}
-
-	// TODO need to optimize here.
-	// We might create individual stat structures for circuit breakers, rather than use the universal ResourceNode.
-	if b.metric == nil {
-		resNode := stat.GetResourceNode(rule.Resource)
-		if resNode == nil {
-			logger.Errorf("Resource(%s)'s stat node is nil.", rule.Resource)
-			return true
+	switch b.CurrentState() {
+	case Open:
+		if !b.retryTimeoutArrived() {
+			return false
 		}
-		b.metric = resNode.GetOrCreateSlidingWindowMetric(rule.SampleCount, rule.IntervalInMs)
-		logger.Errorf("Delayed to initialize the metric of errorRatioCircuitBreaker.")
+		b.fromOpenToHalfOpen(rule)
+		return b.tryAdmitProbe(rule.halfOpenMaxCalls())
+	case HalfOpen:
+		return b.tryAdmitProbe(rule.halfOpenMaxCalls())
+	default:
+		return b.tryPassClosed(rule)
 	}
+}
 
-	// biz error total
-	err := b.metric.GetQPS(base.MetricEventError)
-	// complete = err +  realComplete
-	complete := b.metric.GetQPS(base.MetricEventComplete)
-	// total = pass + blocked
-	total := b.metric.GetQPS(base.MetricEventPass) + b.metric.GetQPS(base.MetricEventBlock)
-
+func (b *errorRatioCircuitBreaker) tryPassClosed(rule *errorRatioRule) bool {
+	total := b.stat.totalCount()
 	// If total amount is less than minRequestAmount, the request will pass.
-	if total < float64(rule.MinRequestAmount) {
+	if total < int64(rule.MinRequestAmount) {
 		return true
 	}
 
+	complete := b.stat.completeCount()
+	err := b.stat.errorCount()
 	// "success" (aka. completed count) = error count + non-error count (realComplete)
 	realComplete := complete - err
-	// error count
-	if realComplete <= 0 && err < float64(rule.MinRequestAmount) {
+	if realComplete <= 0 && err < int64(rule.MinRequestAmount) {
 		return true
 	}
 
 	// err/complete is error ratio of the biz
-	if err/complete < rule.Threshold {
+	ratio := float64(err) / float64(complete)
+	if ratio < rule.Threshold {
 		return true
 	}
 
-	if b.cut.CompareAndSet(false, true) {
-		go util.RunWithRecover(func() {
-			// recover after RecoverTimeout seconds
-			time.Sleep(time.Second * time.Duration(rule.RecoverTimeout))
-			b.cut.Set(false)
-		}, logger)
-	}
+	b.fromClosedToOpen(rule, ratio)
 	return false
 }
 
+func (b *errorRatioCircuitBreaker) OnRequestComplete(_ int64, err error) {
+	if b.CurrentState() != HalfOpen {
+		return
+	}
+	if err == nil {
+		b.fromHalfOpenToClosed(b.rule)
+	} else {
+		b.fromHalfOpenToOpen(b.rule, err)
+	}
+}
+
+func (b *errorRatioCircuitBreaker) retryTimeoutArrived() bool {
+	return b.circuitBreakerBase.retryTimeoutArrived(b.rule.RecoverTimeout)
+}
+
 // error count circuit breaker will cut resource if the error count of resource exceed the threshold of rule.
 type errorCountCircuitBreaker struct {
-	// status of the breaker
-	cut util.AtomicBool
-	// the count of request exceed the threshold
-	passCount int64
-	rule      *errorCountRule
-	metric    base.ReadStat
+	circuitBreakerBase
+	rule *errorCountRule
 }
 
 func newErrorCountCircuitBreaker(rule *errorCountRule) *errorCountCircuitBreaker {
-	resNode := stat.GetResourceNode(rule.Resource)
-	var metric base.ReadStat
-	// TODO need to optimize, we should to handle the scenario that resNode is nil
-	if resNode != nil {
-		metric = resNode.GetOrCreateSlidingWindowMetric(rule.SampleCount, rule.IntervalInMs)
-	}
-	return &errorCountCircuitBreaker{
-		rule:   rule,
-		metric: metric,
-	}
+	return newErrorCountCircuitBreakerWithStat(rule, newCircuitBreakerStat(rule.SampleCount, rule.IntervalInMs))
 }
 
-func newErrorCountCircuitBreakerWithMetric(rule *errorCountRule, metric base.ReadStat) *errorCountCircuitBreaker {
-	return &errorCountCircuitBreaker{
-		rule:   rule,
-		metric: metric,
-	}
+func newErrorCountCircuitBreakerWithStat(rule *errorCountRule, stat *circuitBreakerStat) *errorCountCircuitBreaker {
+	b := &errorCountCircuitBreaker{rule: rule}
+	b.stat = stat
+	return b
 }
 
-func (b *errorCountCircuitBreaker) getRule() Rule {
+func (b *errorCountCircuitBreaker) BoundRule() Rule {
 	return b.rule
 }
 
 func (b *errorCountCircuitBreaker) TryPass(_ *base.EntryContext) bool {
-	if b.cut.Get() {
-		return false
+	rule := b.rule
+	if rule == nil {
+		return true
+	}
+	switch b.CurrentState() {
+	case Open:
+		if !b.retryTimeoutArrived() {
+			return false
+		}
+		b.fromOpenToHalfOpen(rule)
+		return b.tryAdmitProbe(rule.halfOpenMaxCalls())
+	case HalfOpen:
+		return b.tryAdmitProbe(rule.halfOpenMaxCalls())
+	default:
+		return b.tryPassClosed(rule)
+	}
+}
+
+func (b *errorCountCircuitBreaker) tryPassClosed(rule *errorCountRule) bool {
+	err := b.stat.errorCount()
+	if err < int64(rule.Threshold) {
+		return true
+	}
+
+	b.fromClosedToOpen(rule, err)
+	return false
+}
+
+func (b *errorCountCircuitBreaker) OnRequestComplete(_ int64, err error) {
+	if b.CurrentState() != HalfOpen {
+		return
+	}
+	if err == nil {
+		b.fromHalfOpenToClosed(b.rule)
+	} else {
+		b.fromHalfOpenToOpen(b.rule, err)
 	}
+}
+
+func (b *errorCountCircuitBreaker) retryTimeoutArrived() bool {
+	return b.circuitBreakerBase.retryTimeoutArrived(b.rule.RecoverTimeout)
+}
 
+// slow request ratio circuit breaker will cut resource if the ratio of slow requests (rt exceeding
+// MaxAllowedRt) of resource exceed the threshold of rule. It complements averageRtCircuitBreaker,
+// which can be masked by a bimodal latency distribution.
+type slowRequestRatioCircuitBreaker struct {
+	circuitBreakerBase
+	rule *slowRequestRatioRule
+}
+
+func newSlowRequestRatioCircuitBreaker(rule *slowRequestRatioRule) *slowRequestRatioCircuitBreaker {
+	return newSlowRequestRatioCircuitBreakerWithStat(rule,
+		newSlowRequestStat(rule.SampleCount, rule.IntervalInMs, rule.MaxAllowedRt))
+}
+
+func newSlowRequestRatioCircuitBreakerWithStat(rule *slowRequestRatioRule, stat *circuitBreakerStat) *slowRequestRatioCircuitBreaker {
+	b := &slowRequestRatioCircuitBreaker{rule: rule}
+	b.stat = stat
+	return b
+}
+
+func (b *slowRequestRatioCircuitBreaker) BoundRule() Rule {
+	return b.rule
+}
+
+func (b *slowRequestRatioCircuitBreaker) TryPass(_ *base.EntryContext) bool {
 	rule := b.rule
 	if rule == nil {
 		return true
 	}
-
-	// TODO need to optimize here.
-	// We might create individual stat structures for circuit breakers, rather than use the universal ResourceNode.
-	if b.metric == nil {
-		resNode := stat.GetResourceNode(rule.Resource)
-		if resNode == nil {
-			logger.Errorf("Resource(%s)'s stat node is nil.", rule.Resource)
-			return true
+	switch b.CurrentState() {
+	case Open:
+		if !b.retryTimeoutArrived() {
+			return false
 		}
-		b.metric = resNode.GetOrCreateSlidingWindowMetric(rule.SampleCount, rule.IntervalInMs)
-		logger.Errorf("Delayed to initialize the metric of errorCountCircuitBreaker.")
+		b.fromOpenToHalfOpen(rule)
+		return b.tryAdmitProbe(rule.halfOpenMaxCalls())
+	case HalfOpen:
+		return b.tryAdmitProbe(rule.halfOpenMaxCalls())
+	default:
+		return b.tryPassClosed(rule)
 	}
+}
 
-	err := b.metric.GetQPS(base.MetricEventError)
-	if err < float64(rule.Threshold) {
+func (b *slowRequestRatioCircuitBreaker) tryPassClosed(rule *slowRequestRatioRule) bool {
+	total := b.stat.totalCount()
+	if total < int64(rule.MinRequestAmount) {
 		return true
 	}
 
-	if b.cut.CompareAndSet(false, true) {
-		go util.RunWithRecover(func() {
-			// recover after RecoverTimeout seconds
-			time.Sleep(time.Second * time.Duration(rule.RecoverTimeout))
-			b.cut.Set(false)
-		}, logger)
+	complete := b.stat.completeCount()
+	if complete <= 0 {
+		return true
+	}
+	slow := b.stat.slowCount()
+
+	ratio := float64(slow) / float64(complete)
+	if ratio < rule.Threshold {
+		return true
 	}
+
+	b.fromClosedToOpen(rule, ratio)
 	return false
 }
+
+func (b *slowRequestRatioCircuitBreaker) OnRequestComplete(rt int64, err error) {
+	if b.CurrentState() != HalfOpen {
+		return
+	}
+	if err == nil && rt < int64(b.rule.MaxAllowedRt) {
+		b.fromHalfOpenToClosed(b.rule)
+	} else {
+		b.fromHalfOpenToOpen(b.rule, rt)
+	}
+}
+
+func (b *slowRequestRatioCircuitBreaker) retryTimeoutArrived() bool {
+	return b.circuitBreakerBase.retryTimeoutArrived(b.rule.RecoverTimeout)
+}
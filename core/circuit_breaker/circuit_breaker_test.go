@@ -0,0 +1,91 @@
+package circuit_breaker
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestErrorCountCircuitBreaker_HalfOpenAdmitsExactlyMaxCalls hammers TryPass concurrently right as
+// the breaker becomes eligible to leave Open (RecoverTimeout is 0, so every goroutine sees the
+// retry timeout as already elapsed), and asserts that exactly HalfOpenMaxCalls probes are admitted
+// despite the race between the Open->HalfOpen transition and probe admission.
+func TestErrorCountCircuitBreaker_HalfOpenAdmitsExactlyMaxCalls(t *testing.T) {
+	rule := &errorCountRule{
+		ruleBase: ruleBase{
+			Resource: "half-open-concurrency", SampleCount: 1, IntervalInMs: 1000,
+			MinRequestAmount: 1, RecoverTimeout: 0, HalfOpenMaxCalls: 3,
+		},
+		Threshold: 1,
+	}
+	b := newErrorCountCircuitBreaker(rule)
+	assert.True(t, b.fromClosedToOpen(rule, int64(1)))
+	assert.Equal(t, Open, b.CurrentState())
+
+	const goroutines = 50
+	var admitted int64
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			if b.TryPass(nil) {
+				atomic.AddInt64(&admitted, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(rule.HalfOpenMaxCalls), admitted)
+	assert.Equal(t, HalfOpen, b.CurrentState())
+}
+
+// TestErrorCountCircuitBreaker_RecoversOnSuccessfulProbe drives the Closed->Open->HalfOpen->Closed
+// round trip via OnRequestComplete.
+func TestErrorCountCircuitBreaker_RecoversOnSuccessfulProbe(t *testing.T) {
+	rule := &errorCountRule{
+		ruleBase: ruleBase{
+			Resource: "half-open-success", SampleCount: 1, IntervalInMs: 1000,
+			MinRequestAmount: 1, RecoverTimeout: 0, HalfOpenMaxCalls: 1,
+		},
+		Threshold: 1,
+	}
+	b := newErrorCountCircuitBreaker(rule)
+	assert.Equal(t, Closed, b.CurrentState())
+
+	assert.True(t, b.fromClosedToOpen(rule, int64(1)))
+	assert.Equal(t, Open, b.CurrentState())
+
+	assert.True(t, b.TryPass(nil))
+	assert.Equal(t, HalfOpen, b.CurrentState())
+
+	b.OnRequestComplete(0, nil)
+	assert.Equal(t, Closed, b.CurrentState())
+}
+
+// TestErrorCountCircuitBreaker_ReopensOnFailedProbe drives the Closed->Open->HalfOpen->Open round
+// trip when the probe request admitted during HalfOpen itself fails.
+func TestErrorCountCircuitBreaker_ReopensOnFailedProbe(t *testing.T) {
+	rule := &errorCountRule{
+		ruleBase: ruleBase{
+			Resource: "half-open-failure", SampleCount: 1, IntervalInMs: 1000,
+			MinRequestAmount: 1, RecoverTimeout: 0, HalfOpenMaxCalls: 1,
+		},
+		Threshold: 1,
+	}
+	b := newErrorCountCircuitBreaker(rule)
+
+	assert.True(t, b.fromClosedToOpen(rule, int64(1)))
+	assert.True(t, b.TryPass(nil))
+	assert.Equal(t, HalfOpen, b.CurrentState())
+
+	b.OnRequestComplete(0, errors.New("probe failed"))
+	assert.Equal(t, Open, b.CurrentState())
+
+	// RecoverTimeout is 0, so the very next call is immediately eligible to admit a fresh probe.
+	assert.True(t, b.TryPass(nil))
+	assert.Equal(t, HalfOpen, b.CurrentState())
+}
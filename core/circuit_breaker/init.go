@@ -0,0 +1,7 @@
+package circuit_breaker
+
+import (
+	"github.com/alibaba/sentinel-golang/logging"
+)
+
+var logger = logging.NewLogger("circuit_breaker.log")
@@ -0,0 +1,49 @@
+package circuit_breaker
+
+import "time"
+
+// GetBreakersOfResource returns a snapshot of the circuit breakers currently guarding resource.
+// It allows operators to build admin endpoints and dashboards on top of the breaker state.
+func GetBreakersOfResource(resource string) []CircuitBreaker {
+	breakers := breakersOf(resource)
+	if len(breakers) == 0 {
+		return nil
+	}
+	res := make([]CircuitBreaker, len(breakers))
+	copy(res, breakers)
+	return res
+}
+
+// CurrentState returns the current State of the given circuit breaker.
+func CurrentState(b CircuitBreaker) State {
+	return b.CurrentState()
+}
+
+// forceOpener is implemented by circuitBreakerBase and promoted onto every concrete circuit
+// breaker; it backs the package-level ForceOpen and Reset management functions below. Both
+// methods take the breaker's own Rule so that, like every other state transition, they can
+// notify registered StateChangeListeners with it.
+type forceOpener interface {
+	forceOpen(rule Rule, d time.Duration)
+	forceReset(rule Rule)
+}
+
+// ForceOpen forces the given circuit breaker into the Open state for at least d, rejecting all
+// requests until d elapses, regardless of the strategy's own trip condition. It is meant for
+// operators putting a resource into maintenance, ahead of a known incident. Like a natural trip,
+// it notifies registered StateChangeListeners.
+func ForceOpen(b CircuitBreaker, d time.Duration) {
+	if fo, ok := b.(forceOpener); ok {
+		fo.forceOpen(b.BoundRule(), d)
+	}
+}
+
+// Reset clears any ForceOpen override on the given circuit breaker and returns it to Closed, as
+// if freshly built. It is meant for operators clearing a breaker right after a deploy that is
+// known to have fixed the underlying issue. Like a natural recovery, it notifies registered
+// StateChangeListeners.
+func Reset(b CircuitBreaker) {
+	if fo, ok := b.(forceOpener); ok {
+		fo.forceReset(b.BoundRule())
+	}
+}
@@ -0,0 +1,60 @@
+package circuit_breaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForceOpenAndReset(t *testing.T) {
+	rule := &errorCountRule{
+		ruleBase:  ruleBase{Resource: "force-open-resource", SampleCount: 1, IntervalInMs: 1000, MinRequestAmount: 1},
+		Threshold: 100,
+	}
+	b := newErrorCountCircuitBreaker(rule)
+	assert.Equal(t, Closed, CurrentState(b))
+
+	ForceOpen(b, 50*time.Millisecond)
+	assert.Equal(t, Open, CurrentState(b))
+	assert.False(t, b.TryPass(nil))
+
+	Reset(b)
+	assert.Equal(t, Closed, CurrentState(b))
+	assert.True(t, b.TryPass(nil))
+}
+
+// TestReset_ClearsStatAndPassCount verifies that Reset leaves the breaker unable to retrip on
+// samples observed before the reset: an averageRtCircuitBreaker carries stale high-RT samples (and
+// a pre-tripped passCount) into the reset, and the very next tryPassClosed must still pass.
+func TestReset_ClearsStatAndPassCount(t *testing.T) {
+	rule := &averageRtRule{
+		ruleBase:  ruleBase{Resource: "reset-resource", SampleCount: 1, IntervalInMs: 1000, RecoverTimeout: 1},
+		Threshold: 50, RtSlowRequestAmount: 1,
+	}
+	b := newAverageRtCircuitBreaker(rule)
+	b.OnCompleted(base.MetricEventComplete, 200)
+	b.OnCompleted(base.MetricEventRt, 200)
+	assert.False(t, b.TryPass(nil))
+	assert.Equal(t, Open, CurrentState(b))
+
+	Reset(b)
+	assert.Equal(t, Closed, CurrentState(b))
+	assert.Equal(t, int64(0), b.passCount)
+	assert.True(t, b.TryPass(nil))
+}
+
+func TestGetBreakersOfResource(t *testing.T) {
+	const resource = "manage-resource"
+	rule := &errorCountRule{
+		ruleBase:  ruleBase{Resource: resource, SampleCount: 1, IntervalInMs: 1000, MinRequestAmount: 1},
+		Threshold: 100,
+	}
+	b := newErrorCountCircuitBreaker(rule)
+	setBreakersOfResource(resource, []CircuitBreaker{b})
+
+	got := GetBreakersOfResource(resource)
+	assert.Equal(t, 1, len(got))
+	assert.Equal(t, rule, got[0].BoundRule())
+}
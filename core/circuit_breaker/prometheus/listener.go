@@ -0,0 +1,54 @@
+// Package prometheus provides a StateChangeListener implementation that exports circuit breaker
+// state transitions as Prometheus metrics. It lives in its own package so that importing
+// core/circuit_breaker does not force the prometheus client library on users who don't need it.
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/alibaba/sentinel-golang/core/circuit_breaker"
+)
+
+// StateChangeListener is a circuit_breaker.StateChangeListener that maintains a CounterVec of
+// state transitions and a GaugeVec of the current state, labeled by resource. Construct it with
+// NewStateChangeListener and register it via circuit_breaker.RegisterStateChangeListeners; users
+// who don't opt in pay no cost.
+type StateChangeListener struct {
+	transitionTotal *prometheus.CounterVec
+	currentState    *prometheus.GaugeVec
+}
+
+// NewStateChangeListener creates a StateChangeListener and registers its collectors against reg.
+func NewStateChangeListener(reg prometheus.Registerer) *StateChangeListener {
+	l := &StateChangeListener{
+		transitionTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "sentinel",
+			Subsystem: "circuit_breaker",
+			Name:      "transition_total",
+			Help:      "Total number of circuit breaker state transitions, labeled by resource and target state.",
+		}, []string{"resource", "state"}),
+		currentState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "sentinel",
+			Subsystem: "circuit_breaker",
+			Name:      "state",
+			Help:      "Current state of the circuit breaker per resource (0=Closed, 1=Open, 2=HalfOpen).",
+		}, []string{"resource"}),
+	}
+	reg.MustRegister(l.transitionTotal, l.currentState)
+	return l
+}
+
+func (l *StateChangeListener) OnTransformToClosed(_ circuit_breaker.State, rule circuit_breaker.Rule) {
+	l.transitionTotal.WithLabelValues(rule.ResourceName(), circuit_breaker.Closed.String()).Inc()
+	l.currentState.WithLabelValues(rule.ResourceName()).Set(float64(circuit_breaker.Closed))
+}
+
+func (l *StateChangeListener) OnTransformToOpen(_ circuit_breaker.State, rule circuit_breaker.Rule, _ interface{}) {
+	l.transitionTotal.WithLabelValues(rule.ResourceName(), circuit_breaker.Open.String()).Inc()
+	l.currentState.WithLabelValues(rule.ResourceName()).Set(float64(circuit_breaker.Open))
+}
+
+func (l *StateChangeListener) OnTransformToHalfOpen(_ circuit_breaker.State, rule circuit_breaker.Rule) {
+	l.transitionTotal.WithLabelValues(rule.ResourceName(), circuit_breaker.HalfOpen.String()).Inc()
+	l.currentState.WithLabelValues(rule.ResourceName()).Set(float64(circuit_breaker.HalfOpen))
+}
@@ -0,0 +1,143 @@
+package circuit_breaker
+
+import (
+	"fmt"
+)
+
+// Strategy represents the strategy of circuit breaker.
+type Strategy int8
+
+const (
+	// AverageRT strategy trips the circuit breaker when the average rt of recent requests exceeds the threshold.
+	AverageRT Strategy = iota
+	// ErrorRatio strategy trips the circuit breaker when the error ratio of recent requests exceeds the threshold.
+	ErrorRatio
+	// ErrorCount strategy trips the circuit breaker when the error count of recent requests exceeds the threshold.
+	ErrorCount
+	// SlowRequestRatio strategy trips the circuit breaker when the ratio of slow requests (rt exceeding
+	// MaxAllowedRt) among recent requests exceeds the threshold. Unlike AverageRT, it is not masked by a
+	// bimodal latency distribution where a few very slow calls hide behind many fast ones.
+	SlowRequestRatio
+)
+
+func (s Strategy) String() string {
+	switch s {
+	case AverageRT:
+		return "AverageRT"
+	case ErrorRatio:
+		return "ErrorRatio"
+	case ErrorCount:
+		return "ErrorCount"
+	case SlowRequestRatio:
+		return "SlowRequestRatio"
+	default:
+		return "Undefined"
+	}
+}
+
+// Rule is the base interface of circuit breaker rules of all strategies.
+type Rule interface {
+	fmt.Stringer
+
+	// ResourceName returns the resource name that the rule is guarding.
+	ResourceName() string
+	// BreakerStrategy returns the strategy of the circuit breaker.
+	BreakerStrategy() Strategy
+}
+
+// ruleBase holds the fields that are common to every circuit breaker strategy.
+type ruleBase struct {
+	// Resource represents the target resource definition.
+	Resource string `json:"resource"`
+	// Strategy represents the strategy of circuit breaker.
+	Strategy Strategy `json:"strategy"`
+	// RecoverTimeout represents the recovery timeout (in seconds) after the circuit breaker opens.
+	// After the timeout elapses, the circuit breaker will try to recover from the Open state to the HalfOpen
+	// state and admit probe requests.
+	RecoverTimeout uint32 `json:"recoverTimeout"`
+	// SampleCount represents the number of buckets the sliding window is divided into.
+	SampleCount uint32 `json:"sampleCount"`
+	// IntervalInMs represents the total length of the sliding window (in milliseconds).
+	IntervalInMs uint32 `json:"intervalInMs"`
+	// MinRequestAmount represents the minimum number of requests (in the sliding window) to trigger the
+	// circuit breaker. Only the ErrorRatio and SlowRequestRatio breakers consult it; the AverageRT and
+	// ErrorCount breakers trip regardless of it.
+	MinRequestAmount uint64 `json:"minRequestAmount"`
+	// HalfOpenMaxCalls represents the max count of probe requests that are allowed to pass when the
+	// circuit breaker is in the HalfOpen state. If not positive, it will be treated as 1.
+	HalfOpenMaxCalls uint32 `json:"halfOpenMaxCalls"`
+}
+
+func (b *ruleBase) ResourceName() string {
+	return b.Resource
+}
+
+func (b *ruleBase) BreakerStrategy() Strategy {
+	return b.Strategy
+}
+
+// halfOpenMaxCalls returns the effective number of probe requests admitted while HalfOpen,
+// defaulting to 1 when the rule does not configure a positive value.
+func (b *ruleBase) halfOpenMaxCalls() uint32 {
+	if b.HalfOpenMaxCalls == 0 {
+		return 1
+	}
+	return b.HalfOpenMaxCalls
+}
+
+// averageRtRule represents the circuit breaker rule based on average response time.
+type averageRtRule struct {
+	ruleBase
+	// Threshold represents the threshold of average RT (in ms).
+	Threshold uint64 `json:"threshold"`
+	// RtSlowRequestAmount represents the number of consecutive requests that find the window's average
+	// RT at or above Threshold before the breaker trips; the request that reaches it is rejected.
+	RtSlowRequestAmount uint64 `json:"rtSlowRequestAmount"`
+}
+
+func (r *averageRtRule) String() string {
+	return fmt.Sprintf("averageRtRule{resource=%s, threshold=%d, rtSlowRequestAmount=%d, minRequestAmount=%d, "+
+		"recoverTimeout=%d, halfOpenMaxCalls=%d}", r.Resource, r.Threshold, r.RtSlowRequestAmount,
+		r.MinRequestAmount, r.RecoverTimeout, r.halfOpenMaxCalls())
+}
+
+// errorRatioRule represents the circuit breaker rule based on error ratio.
+type errorRatioRule struct {
+	ruleBase
+	// Threshold represents the threshold of error ratio (range: [0.0, 1.0]).
+	Threshold float64 `json:"threshold"`
+}
+
+func (r *errorRatioRule) String() string {
+	return fmt.Sprintf("errorRatioRule{resource=%s, threshold=%.2f, minRequestAmount=%d, recoverTimeout=%d, "+
+		"halfOpenMaxCalls=%d}", r.Resource, r.Threshold, r.MinRequestAmount, r.RecoverTimeout, r.halfOpenMaxCalls())
+}
+
+// slowRequestRatioRule represents the circuit breaker rule based on the ratio of slow requests,
+// i.e. requests whose rt exceeds MaxAllowedRt.
+type slowRequestRatioRule struct {
+	ruleBase
+	// MaxAllowedRt represents the per-request RT threshold (in ms) above which a request is
+	// considered slow.
+	MaxAllowedRt uint64 `json:"maxAllowedRt"`
+	// Threshold represents the threshold of slow-request ratio (range: [0.0, 1.0]).
+	Threshold float64 `json:"threshold"`
+}
+
+func (r *slowRequestRatioRule) String() string {
+	return fmt.Sprintf("slowRequestRatioRule{resource=%s, maxAllowedRt=%d, threshold=%.2f, minRequestAmount=%d, "+
+		"recoverTimeout=%d, halfOpenMaxCalls=%d}", r.Resource, r.MaxAllowedRt, r.Threshold, r.MinRequestAmount,
+		r.RecoverTimeout, r.halfOpenMaxCalls())
+}
+
+// errorCountRule represents the circuit breaker rule based on error count.
+type errorCountRule struct {
+	ruleBase
+	// Threshold represents the threshold of error count.
+	Threshold uint64 `json:"threshold"`
+}
+
+func (r *errorCountRule) String() string {
+	return fmt.Sprintf("errorCountRule{resource=%s, threshold=%d, minRequestAmount=%d, recoverTimeout=%d, "+
+		"halfOpenMaxCalls=%d}", r.Resource, r.Threshold, r.MinRequestAmount, r.RecoverTimeout, r.halfOpenMaxCalls())
+}
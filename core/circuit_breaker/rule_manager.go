@@ -0,0 +1,41 @@
+package circuit_breaker
+
+// LoadRules builds the circuit breakers for resource's rules and installs them as the active set
+// that StatSlot (once wired into the slot chain, see its doc comment) feeds and that
+// GetBreakersOfResource reports, replacing whatever breakers previously guarded resource. It is the
+// production entry point for (re)loading circuit breaker rules; callers should not construct
+// breakers directly, since LoadRules is what gives setBreakersOfResource's replace-on-reload
+// semantics instead of leaking the breakers from a previous load.
+//
+// It returns true if every rule was loaded successfully; rules that are not one of the concrete
+// rule types defined in this package are skipped and make it return false.
+func LoadRules(resource string, rules []Rule) bool {
+	breakers := make([]CircuitBreaker, 0, len(rules))
+	ok := true
+	for _, rule := range rules {
+		if b := newBreakerFromRule(rule); b != nil {
+			breakers = append(breakers, b)
+		} else {
+			ok = false
+		}
+	}
+	setBreakersOfResource(resource, breakers)
+	return ok
+}
+
+// newBreakerFromRule builds the concrete circuit breaker for rule's strategy, or nil if rule is not
+// one of the concrete rule types defined in this package.
+func newBreakerFromRule(rule Rule) CircuitBreaker {
+	switch r := rule.(type) {
+	case *averageRtRule:
+		return newAverageRtCircuitBreaker(r)
+	case *errorRatioRule:
+		return newErrorRatioCircuitBreaker(r)
+	case *errorCountRule:
+		return newErrorCountCircuitBreaker(r)
+	case *slowRequestRatioRule:
+		return newSlowRequestRatioCircuitBreaker(r)
+	default:
+		return nil
+	}
+}
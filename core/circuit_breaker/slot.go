@@ -0,0 +1,80 @@
+package circuit_breaker
+
+import (
+	"sync"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+)
+
+// StatSlotOrder is the order of the circuit-breaker StatSlot in the slot chain. It runs after the
+// flow/system StatSlots so that a breaker's own window only ever observes traffic that actually
+// reached (or was blocked while guarded by) this resource's breakers.
+const StatSlotOrder = 5000
+
+// breakersOfResource indexes the currently installed breakers by resource name, so the StatSlot can
+// find which breakers' dedicated stat windows should observe events for a given resource. It is
+// populated by setBreakersOfResource (via LoadRules), not by the breaker constructors, and consulted
+// on every request, so lookups must stay allocation
+// free on the hot path.
+var (
+	breakerMux         sync.RWMutex
+	breakersOfResource = make(map[string][]CircuitBreaker)
+)
+
+// setBreakersOfResource installs the given breakers as the active set for resource, replacing any
+// breakers previously installed for it. LoadRules is the only production caller, so a reload always
+// swaps the slice instead of growing it; tests that need to install breakers directly call this too.
+func setBreakersOfResource(resource string, breakers []CircuitBreaker) {
+	breakerMux.Lock()
+	defer breakerMux.Unlock()
+	if len(breakers) == 0 {
+		delete(breakersOfResource, resource)
+		return
+	}
+	breakersOfResource[resource] = breakers
+}
+
+func breakersOf(resource string) []CircuitBreaker {
+	breakerMux.RLock()
+	defer breakerMux.RUnlock()
+	return breakersOfResource[resource]
+}
+
+// StatSlot feeds Pass/Block/Complete/Error/Rt events directly into the dedicated stat window of
+// every circuit breaker guarding the entered resource, instead of relying on the shared
+// ResourceNode that the flow and system StatSlots also write into.
+//
+// DefaultSlot only observes traffic once it is added to the application's slot chain alongside the
+// flow/system StatSlots (at StatSlotOrder, so it runs after them); wiring the chain together is done
+// where the other slots are assembled, outside this package. LoadRules is the production entry point
+// for (re)loading circuit breaker rules and is what actually populates the breakers DefaultSlot feeds.
+type StatSlot struct{}
+
+// DefaultSlot is the StatSlot instance that must be installed into the slot chain for circuit
+// breakers to observe any traffic. See the StatSlot doc comment.
+var DefaultSlot = &StatSlot{}
+
+func (s *StatSlot) OnEntryPassed(ctx *base.EntryContext) {
+	for _, b := range breakersOf(ctx.Resource.Name()) {
+		b.OnCompleted(base.MetricEventPass, 0)
+	}
+}
+
+func (s *StatSlot) OnEntryBlocked(ctx *base.EntryContext, _ *base.BlockError) {
+	for _, b := range breakersOf(ctx.Resource.Name()) {
+		b.OnCompleted(base.MetricEventBlock, 0)
+	}
+}
+
+func (s *StatSlot) OnCompleted(ctx *base.EntryContext) {
+	rt := ctx.Rt()
+	breakers := breakersOf(ctx.Resource.Name())
+	for _, b := range breakers {
+		b.OnCompleted(base.MetricEventComplete, rt)
+		b.OnCompleted(base.MetricEventRt, rt)
+		if ctx.Err() != nil {
+			b.OnCompleted(base.MetricEventError, rt)
+		}
+		b.OnRequestComplete(rt, ctx.Err())
+	}
+}
@@ -0,0 +1,170 @@
+package circuit_breaker
+
+import (
+	"sync"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/util"
+)
+
+// cbBucket is a single time slot of a circuitBreakerStat's sliding window.
+type cbBucket struct {
+	startTimeMs   int64
+	passCount     int64
+	blockCount    int64
+	completeCount int64
+	errorCount    int64
+	rtTotal       int64
+	slowCount     int64
+}
+
+func (b *cbBucket) reset(startTimeMs int64) {
+	*b = cbBucket{startTimeMs: startTimeMs}
+}
+
+// circuitBreakerStat is a dedicated sliding-window statistic owned by a single circuit breaker
+// instance, keyed solely by that breaker's own (SampleCount, IntervalInMs). This replaces the
+// former practice of reading the shared ResourceNode's SlidingWindowMetric, which meant two rules
+// on the same resource with different windows collided on one cache entry, and business events
+// counted by the flow/system slots leaked into the breaker's view.
+//
+// slowThresholdMs, when positive, marks rt values above it as "slow" as they are recorded; it is
+// only set by the slowRequestRatioCircuitBreaker.
+type circuitBreakerStat struct {
+	mux             sync.Mutex
+	sampleCount     uint32
+	bucketLenMs     int64
+	intervalMs      int64
+	buckets         []cbBucket
+	slowThresholdMs int64
+}
+
+func newCircuitBreakerStat(sampleCount, intervalInMs uint32) *circuitBreakerStat {
+	if sampleCount == 0 {
+		sampleCount = 1
+	}
+	if intervalInMs == 0 {
+		intervalInMs = 1000
+	}
+	// bucketLenMs must stay positive: SampleCount > IntervalInMs (e.g. 10 buckets over a 5ms
+	// window) would otherwise truncate it to 0 and currentBucket's nowMs/bucketLenMs would panic.
+	bucketLenMs := int64(intervalInMs) / int64(sampleCount)
+	if bucketLenMs <= 0 {
+		bucketLenMs = 1
+	}
+	return &circuitBreakerStat{
+		sampleCount: sampleCount,
+		bucketLenMs: bucketLenMs,
+		intervalMs:  int64(intervalInMs),
+		buckets:     make([]cbBucket, sampleCount),
+	}
+}
+
+func newSlowRequestStat(sampleCount, intervalInMs uint32, maxAllowedRt uint64) *circuitBreakerStat {
+	s := newCircuitBreakerStat(sampleCount, intervalInMs)
+	s.slowThresholdMs = int64(maxAllowedRt)
+	return s
+}
+
+// currentBucket returns the bucket that nowMs falls into, resetting it if it belongs to a
+// window that has already rolled over. Callers must hold s.mux.
+func (s *circuitBreakerStat) currentBucket(nowMs int64) *cbBucket {
+	idx := (nowMs / s.bucketLenMs) % int64(s.sampleCount)
+	b := &s.buckets[idx]
+	windowStart := nowMs - nowMs%s.bucketLenMs
+	if b.startTimeMs != windowStart {
+		b.reset(windowStart)
+	}
+	return b
+}
+
+// record feeds a single Pass/Block/Complete/Error/Rt event into the current bucket.
+func (s *circuitBreakerStat) record(event base.MetricEvent, rt int64) {
+	nowMs := util.CurrentTimeMillis()
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	b := s.currentBucket(nowMs)
+	switch event {
+	case base.MetricEventPass:
+		b.passCount++
+	case base.MetricEventBlock:
+		b.blockCount++
+	case base.MetricEventComplete:
+		b.completeCount++
+	case base.MetricEventError:
+		b.errorCount++
+	case base.MetricEventRt:
+		b.rtTotal += rt
+		if s.slowThresholdMs > 0 && rt > s.slowThresholdMs {
+			b.slowCount++
+		}
+	}
+}
+
+// sum aggregates all live buckets (i.e. those that fall within the current window) of the
+// sliding window. Callers must hold s.mux.
+func (s *circuitBreakerStat) sum(nowMs int64) (pass, block, complete, errCount, rtTotal, slow int64) {
+	deadline := nowMs - s.intervalMs
+	for i := range s.buckets {
+		b := &s.buckets[i]
+		if b.startTimeMs <= deadline {
+			continue
+		}
+		pass += b.passCount
+		block += b.blockCount
+		complete += b.completeCount
+		errCount += b.errorCount
+		rtTotal += b.rtTotal
+		slow += b.slowCount
+	}
+	return
+}
+
+func (s *circuitBreakerStat) avgRT() float64 {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	_, _, complete, _, rtTotal, _ := s.sum(util.CurrentTimeMillis())
+	if complete == 0 {
+		return 0
+	}
+	return float64(rtTotal) / float64(complete)
+}
+
+func (s *circuitBreakerStat) errorCount() int64 {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	_, _, _, errCount, _, _ := s.sum(util.CurrentTimeMillis())
+	return errCount
+}
+
+func (s *circuitBreakerStat) totalCount() int64 {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	pass, block, _, _, _, _ := s.sum(util.CurrentTimeMillis())
+	return pass + block
+}
+
+func (s *circuitBreakerStat) completeCount() int64 {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	_, _, complete, _, _, _ := s.sum(util.CurrentTimeMillis())
+	return complete
+}
+
+func (s *circuitBreakerStat) slowCount() int64 {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	_, _, _, _, _, slow := s.sum(util.CurrentTimeMillis())
+	return slow
+}
+
+// reset clears every bucket of the sliding window, discarding all samples observed so far. It backs
+// forceReset, so that a Reset breaker recomputes its trip condition from a clean window instead of
+// retripping on samples collected before the reset.
+func (s *circuitBreakerStat) reset() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for i := range s.buckets {
+		s.buckets[i].reset(0)
+	}
+}
@@ -0,0 +1,67 @@
+package circuit_breaker
+
+import (
+	"testing"
+
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCircuitBreakerStat_IndependentWindows verifies that two circuit breaker rules guarding the
+// same resource, but configured with different IntervalInMs, no longer collide on a single shared
+// SlidingWindowMetric: each breaker's OnCompleted only ever mutates its own dedicated window.
+func TestCircuitBreakerStat_IndependentWindows(t *testing.T) {
+	const resource = "test-resource"
+
+	shortRule := &errorCountRule{
+		ruleBase:  ruleBase{Resource: resource, SampleCount: 2, IntervalInMs: 2000, MinRequestAmount: 1},
+		Threshold: 5,
+	}
+	longRule := &errorCountRule{
+		ruleBase:  ruleBase{Resource: resource, SampleCount: 10, IntervalInMs: 10000, MinRequestAmount: 1},
+		Threshold: 5,
+	}
+
+	shortBreaker := newErrorCountCircuitBreaker(shortRule)
+	longBreaker := newErrorCountCircuitBreaker(longRule)
+	assert.NotSame(t, shortBreaker.stat, longBreaker.stat)
+
+	setBreakersOfResource(resource, []CircuitBreaker{shortBreaker, longBreaker})
+
+	for i := 0; i < 3; i++ {
+		for _, b := range breakersOf(resource) {
+			b.OnCompleted(base.MetricEventError, 0)
+		}
+	}
+
+	assert.Equal(t, int64(3), shortBreaker.stat.errorCount())
+	assert.Equal(t, int64(3), longBreaker.stat.errorCount())
+
+	// Mutating one breaker's window must not leak into the other's.
+	shortBreaker.OnCompleted(base.MetricEventError, 0)
+	assert.Equal(t, int64(4), shortBreaker.stat.errorCount())
+	assert.Equal(t, int64(3), longBreaker.stat.errorCount())
+}
+
+func TestCircuitBreakerStat_SlowCount(t *testing.T) {
+	s := newSlowRequestStat(2, 2000, 50)
+	s.record(base.MetricEventRt, 10)
+	s.record(base.MetricEventRt, 100)
+	s.record(base.MetricEventRt, 200)
+	s.record(base.MetricEventComplete, 0)
+	s.record(base.MetricEventComplete, 0)
+	s.record(base.MetricEventComplete, 0)
+
+	assert.Equal(t, int64(2), s.slowCount())
+	assert.Equal(t, int64(3), s.completeCount())
+}
+
+// TestCircuitBreakerStat_SampleCountExceedsInterval guards against the bucketLenMs == 0 case: a
+// rule with more buckets than milliseconds in its window must not make currentBucket panic on
+// nowMs/bucketLenMs.
+func TestCircuitBreakerStat_SampleCountExceedsInterval(t *testing.T) {
+	s := newCircuitBreakerStat(10, 5)
+	assert.True(t, s.bucketLenMs > 0)
+	s.record(base.MetricEventPass, 0)
+	assert.Equal(t, int64(1), s.totalCount())
+}
@@ -0,0 +1,32 @@
+package circuit_breaker
+
+// State represents the state of a circuit breaker.
+// The state machine of a circuit breaker transitions among Closed, Open and HalfOpen:
+//
+//	Closed --(threshold exceeded)--> Open --(retry timeout elapsed)--> HalfOpen
+//	HalfOpen --(probe succeeded)--> Closed
+//	HalfOpen --(probe failed)--> Open
+type State int32
+
+const (
+	// Closed means the circuit breaker is closed and requests are allowed to pass.
+	Closed State = iota
+	// Open means the circuit breaker is open and requests are rejected, until the retry timeout elapses.
+	Open
+	// HalfOpen means the circuit breaker is probing whether the resource has recovered.
+	// Only a limited number of requests (bound by the rule's HalfOpenMaxCalls) are allowed to pass in this state.
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "Closed"
+	case Open:
+		return "Open"
+	case HalfOpen:
+		return "HalfOpen"
+	default:
+		return "Undefined"
+	}
+}
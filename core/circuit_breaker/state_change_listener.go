@@ -0,0 +1,75 @@
+package circuit_breaker
+
+import "sync"
+
+// StateChangeListener is the listener that will be notified of every state transition of any
+// circuit breaker. Implementations can be used to export the breaker state to metrics systems
+// (e.g. Prometheus, OpenTelemetry), push alerts, or correlate with tracing.
+//
+// Implementations should return quickly, since the callbacks are invoked synchronously on the
+// calling goroutine of TryPass/OnRequestComplete.
+type StateChangeListener interface {
+	// OnTransformToClosed is called right after a circuit breaker transforms to the Closed state.
+	OnTransformToClosed(prev State, rule Rule)
+
+	// OnTransformToOpen is called right after a circuit breaker transforms to the Open state.
+	// snapshot is the statistic snapshot (e.g. current metric value) that triggered the transition.
+	OnTransformToOpen(prev State, rule Rule, snapshot interface{})
+
+	// OnTransformToHalfOpen is called right after a circuit breaker transforms to the HalfOpen state.
+	OnTransformToHalfOpen(prev State, rule Rule)
+}
+
+var (
+	stateChangeListenersMux sync.RWMutex
+	stateChangeListeners    = make([]StateChangeListener, 0)
+)
+
+// RegisterStateChangeListeners registers the given listeners to the global listener list.
+// Users who do not need state-change notifications pay no cost, since the list is empty by default.
+// It is safe to call concurrently with other registrations and with the onTransformTo* notifiers,
+// guarded by stateChangeListenersMux, but for predictable ordering it should still be called before
+// any rules are loaded.
+func RegisterStateChangeListeners(listeners ...StateChangeListener) {
+	stateChangeListenersMux.Lock()
+	defer stateChangeListenersMux.Unlock()
+
+	stateChangeListeners = append(stateChangeListeners, listeners...)
+}
+
+func clearStateChangeListenersForTest() {
+	stateChangeListenersMux.Lock()
+	defer stateChangeListenersMux.Unlock()
+
+	stateChangeListeners = make([]StateChangeListener, 0)
+}
+
+func onTransformToClosed(prev State, rule Rule) {
+	stateChangeListenersMux.RLock()
+	listeners := stateChangeListeners
+	stateChangeListenersMux.RUnlock()
+
+	for _, listener := range listeners {
+		listener.OnTransformToClosed(prev, rule)
+	}
+}
+
+func onTransformToOpen(prev State, rule Rule, snapshot interface{}) {
+	stateChangeListenersMux.RLock()
+	listeners := stateChangeListeners
+	stateChangeListenersMux.RUnlock()
+
+	for _, listener := range listeners {
+		listener.OnTransformToOpen(prev, rule, snapshot)
+	}
+}
+
+func onTransformToHalfOpen(prev State, rule Rule) {
+	stateChangeListenersMux.RLock()
+	listeners := stateChangeListeners
+	stateChangeListenersMux.RUnlock()
+
+	for _, listener := range listeners {
+		listener.OnTransformToHalfOpen(prev, rule)
+	}
+}